@@ -0,0 +1,108 @@
+// Package accesskey emite y resuelve los pares (access key, secret key) que
+// usan los contribuidores del centro de estudiantes para autenticar subidas
+// de planes por carrera. Cada clave persiste como un objeto JSON bajo el
+// prefix 'accesskeys/' del mismo store de planes, análogo a cómo ya se
+// guarda la metadata de cada plan. Al persistir a través de store.Store en
+// vez de un *s3.Client crudo, la resolución de access keys funciona igual
+// con FIUBA_STORE=fs, lo que la hace ejercitable en tests herméticos.
+package accesskey
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
+)
+
+const prefix = "accesskeys/"
+
+// Record es una clave de acceso emitida, junto con las carreras que puede
+// subir. El secreto viaja en texto plano dentro del objeto: al igual que
+// con una API key clásica, quien pueda leer el bucket ya tiene acceso de
+// administrador, así que no hay ganancia en hashearlo acá.
+type Record struct {
+	AccessKey string   `json:"accessKey"`
+	SecretKey string   `json:"secretKey"`
+	Carreras  []string `json:"carreras"`
+	CreatedAt string   `json:"createdAt"`
+}
+
+// PuedeSubir indica si la clave tiene ACL para subir planes de la carrera
+// dada.
+func (rec *Record) PuedeSubir(carrera string) bool {
+	for _, c := range rec.Carreras {
+		if c == carrera {
+			return true
+		}
+	}
+	return false
+}
+
+// Issue genera un nuevo par access-key/secret-key (8 y 32 bytes
+// respectivamente, codificados en base32) con el ACL de carreras indicado, y
+// lo persiste en el store.
+func Issue(ctx context.Context, st store.Store, carreras []string, emitidaEn string) (*Record, error) {
+	accessKey, err := randomBase32(8)
+	if err != nil {
+		return nil, fmt.Errorf("error generando access key: %w", err)
+	}
+
+	secretKey, err := randomBase32(32)
+	if err != nil {
+		return nil, fmt.Errorf("error generando secret key: %w", err)
+	}
+
+	rec := &Record{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Carreras:  carreras,
+		CreatedAt: emitidaEn,
+	}
+
+	body, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("error serializando access key: %w", err)
+	}
+
+	if err := st.Put(ctx, prefix+accessKey+".json", bytes.NewReader(body), nil); err != nil {
+		return nil, fmt.Errorf("error guardando access key: %w", err)
+	}
+
+	return rec, nil
+}
+
+// Lookup resuelve una access key a su Record, o devuelve un error si no
+// existe.
+func Lookup(ctx context.Context, st store.Store, accessKey string) (*Record, error) {
+	obj, err := st.Get(ctx, prefix+accessKey+".json")
+	if err != nil {
+		return nil, fmt.Errorf("access key desconocida")
+	}
+
+	defer obj.Close()
+
+	raw, err := io.ReadAll(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error leyendo access key: %w", err)
+	}
+
+	var rec Record
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, fmt.Errorf("error deserializando access key: %w", err)
+	}
+
+	return &rec, nil
+}
+
+func randomBase32(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf), nil
+}
@@ -0,0 +1,48 @@
+package accesskey
+
+import (
+	"context"
+	"testing"
+
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
+)
+
+func TestIssueLookupRoundtrip(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+
+	emitida, err := Issue(ctx, st, []string{"Ingeniería en Informática"}, "2026-07-29T00:00:00Z")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	resuelta, err := Lookup(ctx, st, emitida.AccessKey)
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+
+	if resuelta.SecretKey != emitida.SecretKey {
+		t.Errorf("SecretKey resuelta = %q, querida %q", resuelta.SecretKey, emitida.SecretKey)
+	}
+	if !resuelta.PuedeSubir("Ingeniería en Informática") {
+		t.Error("PuedeSubir(\"Ingeniería en Informática\") = false, quería true")
+	}
+	if resuelta.PuedeSubir("Ingeniería Civil") {
+		t.Error("PuedeSubir(\"Ingeniería Civil\") = true, quería false (fuera de la ACL)")
+	}
+}
+
+func TestLookupAccessKeyDesconocida(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	if _, err := Lookup(context.Background(), st, "no-existe"); err == nil {
+		t.Error("Lookup de access key desconocida devolvió nil, quería un error")
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/regexPattern/fiuba-reviews-backend/api/accesskey"
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
+)
+
+// BUCKET es el mismo bucket donde se guardan los planes; las access keys
+// viven ahí bajo el prefix 'accesskeys/' (ver paquete accesskey).
+const BUCKET string = "fiuba-reviews-siu"
+
+type emitirAccessKeyBody struct {
+	Carreras []string `json:"carreras"`
+}
+
+// HandlerAccessKeys atiende POST /accesskeys para emitir una nueva access
+// key con ACL sobre las carreras indicadas. Es la única forma de
+// provisionar una access key (incluida la primera) en un deploy nuevo, así
+// que queda detrás de un token de administrador fijo (env
+// 'FIUBA_ADMIN_TOKEN') en vez de una access key propia: todavía no existe
+// ninguna para firmar la request.
+func HandlerAccessKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	if r.Method != "POST" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	adminToken := os.Getenv("FIUBA_ADMIN_TOKEN")
+	provisto := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	if adminToken == "" || !hmac.Equal([]byte(adminToken), []byte(provisto)) {
+		w.WriteHeader(http.StatusUnauthorized)
+
+		_, err := w.Write([]byte("Token de administrador inválido o no configurado."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	defer r.Body.Close()
+
+	var body emitirAccessKeyBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte("Body inválido, se espera JSON '{\"carreras\": [...]}'."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	if len(body.Carreras) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte("La access key necesita al menos una carrera en su ACL."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	st, err := store.New(ctx, BUCKET)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	rec, err := accesskey.Issue(ctx, st, body.Carreras, time.Now().Format(time.RFC3339))
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		_, err := w.Write([]byte("Error interno emitiendo la access key."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	recJson, err := json.Marshal(rec)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if _, err := w.Write(recJson); err != nil {
+		slog.Error(err.Error())
+	}
+}
@@ -3,9 +3,13 @@ package api
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
 	"os"
 
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,11 +17,14 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/regexPattern/fiuba-reviews-backend/api/accesskey"
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
 	"github.com/regexPattern/fiuba-reviews/scraper"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
@@ -36,6 +43,15 @@ type cuatri struct {
 	Anio   int `json:"anio"`
 }
 
+// planVersion representa una versión histórica de un plan almacenada en S3,
+// tal como queda expuesta por GET /plans/{key}/versions.
+type planVersion struct {
+	VersionId    string `json:"versionId"`
+	LastModified string `json:"lastModified"`
+	IsLatest     bool   `json:"isLatest"`
+	Size         int64  `json:"size"`
+}
+
 func init() {
 	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		AddSource: true,
@@ -46,6 +62,16 @@ func init() {
 func HandlerScraperSiu(w http.ResponseWriter, r *http.Request) {
 	ctx := context.Background()
 
+	if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/versions") {
+		handlerVersions(ctx, w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/scraper/uploads/") || r.URL.Path == "/scraper/uploads" {
+		handlerUploads(ctx, w, r)
+		return
+	}
+
 	switch r.Method {
 	case "GET":
 		handlerGet(ctx, w)
@@ -56,7 +82,17 @@ func HandlerScraperSiu(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-func handlerGet(ctx context.Context, w http.ResponseWriter) {
+// handlerVersions atiende GET /plans/{key}/versions. Sin el query param
+// 'versionId' devuelve el listado de versiones del objeto; con él, devuelve
+// el contenido de esa versión puntual. Esto permite al frontend hacer
+// rollback de un scrapeo que haya sobreescrito un plan curado a mano.
+//
+// A diferencia del resto de los handlers, este usa un *s3.Client crudo en
+// vez de store.Store: el versionado de objetos es una feature nativa del
+// bucket S3 (ListObjectVersions) sin equivalente en FSStore/MinioStore, así
+// que no hay forma de correr este endpoint en particular con
+// FIUBA_STORE=fs; el resto de la API sí corre localmente.
+func handlerVersions(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 	cfg, err := config.LoadDefaultConfig(ctx)
 	if err != nil {
 		slog.Error(err.Error())
@@ -73,16 +109,63 @@ func handlerGet(ctx context.Context, w http.ResponseWriter) {
 
 	client := s3.NewFromConfig(cfg)
 
-	bucket, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+	key := objKeyDesdePath(r.URL.Path)
+	if key == "" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte("No se pudo determinar el plan solicitado."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	if versionId := r.URL.Query().Get("versionId"); versionId != "" {
+		objLogger := slog.Default().With("objKey", key, "versionId", versionId)
+
+		obj, err := client.GetObject(ctx, &s3.GetObjectInput{
+			Bucket:    aws.String(BUCKET),
+			Key:       aws.String(key),
+			VersionId: aws.String(versionId),
+		})
+
+		if err != nil {
+			objLogger.Error(err.Error())
+
+			w.WriteHeader(http.StatusNotFound)
+
+			_, err := w.Write([]byte("No se encontró la versión solicitada."))
+			if err != nil {
+				slog.Error(err.Error())
+			}
+
+			return
+		}
+
+		defer obj.Body.Close()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		if _, err := io.Copy(w, obj.Body); err != nil {
+			objLogger.Error(err.Error())
+		}
+
+		return
+	}
+
+	out, err := client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
 		Bucket: aws.String(BUCKET),
+		Prefix: aws.String(key),
 	})
 
 	if err != nil {
-		slog.Error(err.Error())
+		slog.Error(err.Error(), "objKey", key)
 
 		w.WriteHeader(http.StatusInternalServerError)
 
-		_, err := w.Write([]byte("Error interno obteniendo listado de la base de datos."))
+		_, err := w.Write([]byte("Error interno al listar versiones del plan."))
 		if err != nil {
 			slog.Error(err.Error())
 		}
@@ -90,44 +173,95 @@ func handlerGet(ctx context.Context, w http.ResponseWriter) {
 		return
 	}
 
-	planes := make([]*plan, 0, len(bucket.Contents))
+	versiones := make([]*planVersion, 0, len(out.Versions))
 
-	for _, obj := range bucket.Contents {
-		objLogger := slog.Default().With("objKey", obj.Key)
+	for _, v := range out.Versions {
+		if aws.ToString(v.Key) != key {
+			continue
+		}
 
-		objHead, err := client.HeadObject(ctx, &s3.HeadObjectInput{
-			Bucket: aws.String(BUCKET),
-			Key:    obj.Key,
+		versiones = append(versiones, &planVersion{
+			VersionId:    aws.ToString(v.VersionId),
+			LastModified: aws.ToTime(v.LastModified).Format("2006-01-02T15:04:05Z07:00"),
+			IsLatest:     aws.ToBool(v.IsLatest),
+			Size:         aws.ToInt64(v.Size),
 		})
+	}
 
+	versionesJson, err := json.Marshal(versiones)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		_, err = w.Write([]byte("Error interno serializando respuesta."))
 		if err != nil {
-			objLogger.Error(err.Error())
+			slog.Error(err.Error())
+		}
 
-			w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
-			_, err := w.Write([]byte("Error interno al obtener planes existentes."))
-			if err != nil {
-				slog.Error(err.Error())
-			}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 
-			return
+	if _, err := w.Write(versionesJson); err != nil {
+		slog.Error(err.Error())
+	}
+}
+
+// objKeyDesdePath extrae el {key} de una ruta '/plans/{key}/versions'.
+func objKeyDesdePath(path string) string {
+	partes := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i, parte := range partes {
+		if parte == "versions" && i > 0 {
+			return partes[i-1]
 		}
+	}
+
+	return ""
+}
 
-		plan, err := parsearMetaDataPlan(objHead)
+func handlerGet(ctx context.Context, w http.ResponseWriter) {
+	st, err := store.New(ctx, BUCKET)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		_, err := w.Write([]byte("Error interno conectando con la base de datos."))
 		if err != nil {
-			objLogger.Error(err.Error())
+			slog.Error(err.Error())
+		}
 
-			w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
 
-			_, err := w.Write([]byte(err.Error()))
-			if err != nil {
-				slog.Error(err.Error())
-			}
+	metas, err := st.List(ctx)
+	if err != nil {
+		slog.Error(err.Error())
 
-			return
+		w.WriteHeader(http.StatusInternalServerError)
+
+		_, err := w.Write([]byte("Error interno obteniendo listado de la base de datos."))
+		if err != nil {
+			slog.Error(err.Error())
 		}
 
-		planes = append(planes, plan)
+		return
+	}
+
+	planes := make([]*plan, 0, len(metas))
+
+	for _, meta := range metas {
+		planes = append(planes, &plan{
+			Carrera: meta.Carrera,
+			Cuatri: cuatri{
+				Numero: meta.CuatriNumero,
+				Anio:   meta.CuatriAnio,
+			},
+		})
 	}
 
 	planesJson, err := json.Marshal(planes)
@@ -154,7 +288,7 @@ func handlerGet(ctx context.Context, w http.ResponseWriter) {
 }
 
 func handlerPost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	st, err := store.New(ctx, BUCKET)
 	if err != nil {
 		slog.Error(err.Error())
 
@@ -168,8 +302,6 @@ func handlerPost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	client := s3.NewFromConfig(cfg)
-
 	defer r.Body.Close()
 
 	contenidoSiu, err := io.ReadAll(r.Body)
@@ -187,6 +319,20 @@ func handlerPost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	accessKeyRec, err := autenticarHmac(ctx, st, r, contenidoSiu)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusUnauthorized)
+
+		_, werr := w.Write([]byte(err.Error()))
+		if werr != nil {
+			slog.Error(werr.Error())
+		}
+
+		return
+	}
+
 	meta, err := scraper.ObtenerMetaData(string(contenidoSiu))
 
 	if err != nil {
@@ -200,6 +346,19 @@ func handlerPost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !accessKeyRec.PuedeSubir(meta.Carrera) {
+		slog.Warn("Access key sin ACL para la carrera.", "carrera", meta.Carrera, "accessKey", accessKeyRec.AccessKey)
+
+		w.WriteHeader(http.StatusForbidden)
+
+		_, err := w.Write([]byte("La access key no tiene permiso para subir planes de esa carrera."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
 	materias := scraper.ObtenerMaterias(meta.Cuatri.Contenido)
 	objBody, err := json.Marshal(materias)
 
@@ -216,49 +375,85 @@ func handlerPost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Se eliminan los diacríticos para generar el file path (object key).
-	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
-	carrera, _, _ := transform.String(t, meta.Carrera)
-	carrera = strings.ToLower(strings.ReplaceAll(carrera, " ", "-"))
-
-	objKey := fmt.Sprintf("%v-%vC-%v.json", carrera, meta.Cuatri.Numero, meta.Cuatri.Anio)
+	objKey, objMeta := planObjKeyYMeta(meta.Carrera, meta.Cuatri.Numero, meta.Cuatri.Anio, objBody)
+
+	// El frontend puede mandar If-Match/If-None-Match para evitar pisar un
+	// plan curado a mano con un re-scrapeo más nuevo. Se propagan al store
+	// para que el Put final también las evalúe de forma nativa (p.ej.
+	// PutObject de S3), pero el único caso real de uso (el cliente ya tiene
+	// el ETag de un plan existente y quiere protegerse de que otro lo haya
+	// modificado) implica que el objeto ya existe, así que el 412 hay que
+	// decidirlo acá contra la metadata que devuelve planYaExiste: si se
+	// dejara que el camino de abajo (200 por contenido idéntico / 409 por
+	// contenido distinto) respondiera primero, el Put condicional nunca se
+	// llegaría a ejecutar y la precondición quedaría sin efecto.
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch != "" {
+		objMeta[store.MetaIfMatch] = ifMatch
+	}
+	ifNoneMatch := r.Header.Get("If-None-Match")
+	if ifNoneMatch != "" {
+		objMeta[store.MetaIfNoneMatch] = ifNoneMatch
+	}
 
-	// Se le hace encoding al valor del key de metadata 'carrera'. Esto debido a
-	// que AWS require este encoding cuando los valores de los headers (los
-	// metadatos son headers) contiene caracters no US-ASCII, como es el caso de
-	// la mayoría de los nombres de las carreras de la facultad.
-	//
-	// Realmente la SDK de S3 se encarga automáticamente de esto, pero por alguna
-	// razón me está arrojando un encoding erróneo de las tildes, así que
-	// prefiero hacerlo manual.
-	//
-	// Más información: https://docs.aws.amazon.com/AmazonS3/latest/userguide/UsingMetadata.html#UserMetadata
-	carreraB64 := base64.StdEncoding.EncodeToString([]byte(meta.Carrera))
-
-	obj := &s3.PutObjectInput{
-		Bucket:          aws.String(BUCKET),
-		Key:             aws.String(objKey),
-		ContentType:     aws.String("application/json"),
-		ContentLanguage: aws.String("es"),
-		Metadata: map[string]string{
-			"carrera":       carreraB64,
-			"cuatri-numero": strconv.Itoa(meta.Cuatri.Numero),
-			"cuatri-anio":   strconv.Itoa(meta.Cuatri.Anio),
-		},
-		Body: bytes.NewReader(objBody),
-	}
-
-	if yaExiste, err := planYaExiste(ctx, client, obj); err != nil {
+	estado, existente, err := planYaExiste(ctx, st, objKey, objMeta["content-sha256"])
+	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
-	} else if yaExiste {
-		w.WriteHeader(http.StatusAccepted)
+	}
+
+	if ifMatch != "" && (estado == planNoExiste || existente.ETag != ifMatch) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		_, err := w.Write([]byte("El plan no existe o fue modificado; el ETag indicado en If-Match ya no es válido."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+	if ifNoneMatch != "" && estado != planNoExiste {
+		w.WriteHeader(http.StatusPreconditionFailed)
+
+		_, err := w.Write([]byte("Ya existe un plan para esa carrera y cuatrimestre (If-None-Match)."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	switch estado {
+	case planExisteMismoContenido:
+		slog.Info("Plan ya existente con mismo contenido, devolviendo idempotente.", "objKey", objKey)
+		w.WriteHeader(http.StatusOK)
+		return
+	case planExisteContenidoDistinto:
+		slog.Warn("Plan-id ya existente con contenido distinto.", "objKey", objKey, "planId", objMeta["plan-id"])
+		w.WriteHeader(http.StatusConflict)
+
+		_, err := w.Write([]byte("Ya existe un plan para esa carrera y cuatrimestre con contenido distinto."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
 		return
 	}
 
-	_, err = client.PutObject(ctx, obj)
+	err = st.Put(ctx, objKey, bytes.NewReader(objBody), objMeta)
 
 	if err != nil {
+		if errors.Is(err, store.ErrPreconditionFailed) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+
+			_, err := w.Write([]byte("El plan fue modificado por otro request; reintentá con el ETag actual."))
+			if err != nil {
+				slog.Error(err.Error())
+			}
+
+			return
+		}
+
 		slog.Error(err.Error())
 
 		w.WriteHeader(http.StatusInternalServerError)
@@ -276,95 +471,150 @@ func handlerPost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 	slog.Info(fmt.Sprintf("Escrito archivo '%v' con éxito.", objKey))
 }
 
-func parsearMetaDataPlan(objHead *s3.HeadObjectOutput) (*plan, error) {
-	meta := objHead.Metadata
+// autenticarHmac valida la cabecera
+// 'Authorization: FIUBA-HMAC-SHA256 key=..., signature=...' de un request,
+// firmando 'datos' (el cuerpo completo en un POST directo; en una subida por
+// partes, un cuerpo vacío al abrirla, el pedazo de cada PATCH, y el digest
+// anunciado al finalizarla) más el timestamp con la
+// secret key de la access key indicada. Devuelve el Record de la access key
+// autenticada para que el caller pueda además chequear su ACL de carreras.
+func autenticarHmac(ctx context.Context, st store.Store, r *http.Request, datos []byte) (*accesskey.Record, error) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "FIUBA-HMAC-SHA256 ") {
+		return nil, fmt.Errorf("falta cabecera Authorization FIUBA-HMAC-SHA256")
+	}
 
-	carreraB64, okCarrera := meta["carrera"]
-	numeroStr, okNum := meta["cuatri-numero"]
-	anioStr, okAnio := meta["cuatri-anio"]
+	campos := map[string]string{}
+	for _, parte := range strings.Split(strings.TrimPrefix(auth, "FIUBA-HMAC-SHA256 "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(parte), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		campos[kv[0]] = kv[1]
+	}
 
-	var err error
+	accessKeyId, firma := campos["key"], campos["signature"]
+	if accessKeyId == "" || firma == "" {
+		return nil, fmt.Errorf("cabecera Authorization incompleta")
+	}
 
-	if !okCarrera {
-		err = fmt.Errorf("Metadato 'carrera' no encontrado.")
-	} else if !okNum {
-		err = fmt.Errorf("Metadato 'cuatri-numero' no encontrado.")
-	} else if !okAnio {
-		err = fmt.Errorf("Metadato 'cuatri-anio' no encontrado.")
+	timestampStr := r.Header.Get("X-Fiuba-Timestamp")
+	if timestampStr == "" {
+		return nil, fmt.Errorf("falta cabecera X-Fiuba-Timestamp")
 	}
 
+	timestamp, err := strconv.ParseInt(timestampStr, 10, 64)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("X-Fiuba-Timestamp inválido")
 	}
 
-	carrera, errCarrera := base64.StdEncoding.DecodeString(carreraB64)
-	numero, errNum := strconv.Atoi(numeroStr)
-	anio, errAnio := strconv.Atoi(anioStr)
-
-	if errCarrera != nil {
-		err = fmt.Errorf("Error al deserializar 'carrera' como string.")
-	} else if errNum != nil {
-		err = fmt.Errorf("Error al deserializar 'cuatri-numero' como entero.")
-	} else if errAnio != nil {
-		err = fmt.Errorf("Error al deserializar 'cuatri-anio' como entero.")
+	skew := time.Since(time.Unix(timestamp, 0))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > 5*time.Minute {
+		return nil, fmt.Errorf("timestamp desfasado por más de 5 minutos")
 	}
 
+	rec, err := accesskey.Lookup(ctx, st, accessKeyId)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("access key inválida")
 	}
 
-	plan := &plan{
-		Carrera: string(carrera),
-		Cuatri: cuatri{
-			Numero: numero,
-			Anio:   anio,
-		},
+	mac := hmac.New(sha256.New, []byte(rec.SecretKey))
+	mac.Write(datos)
+	mac.Write([]byte(timestampStr))
+	esperada := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(esperada), []byte(firma)) {
+		return nil, fmt.Errorf("firma inválida")
 	}
 
-	return plan, nil
+	return rec, nil
 }
 
-func planYaExiste(ctx context.Context, client *s3.Client, newObj *s3.PutObjectInput) (bool, error) {
-	bucket, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
-		Bucket: aws.String(BUCKET),
-	})
+// planCompararEstado describe el resultado de comparar un plan entrante
+// contra lo que ya está guardado bajo el mismo objKey.
+type planCompararEstado int
+
+const (
+	planNoExiste planCompararEstado = iota
+	planExisteMismoContenido
+	planExisteContenidoDistinto
+)
+
+// planYaExiste resuelve si el objKey destino ya existe haciendo un único
+// Head (en vez de recorrer todo el bucket), y compara el hash de contenido
+// para distinguir un re-scrapeo idéntico (cache hit real) de una colisión de
+// plan-id con contenido distinto. Devuelve también la metadata ya existente
+// (vacía si no existe) para que el caller pueda validar If-Match/If-None-Match
+// contra su ETag sin pagar un segundo Head.
+func planYaExiste(ctx context.Context, st store.Store, objKey, contentSha256 string) (planCompararEstado, store.PlanMeta, error) {
+	existente, err := st.Head(ctx, objKey)
 
 	if err != nil {
-		slog.Error(err.Error())
-		return false, fmt.Errorf("Error interno al comparar con planes ya existentes.")
+		if errors.Is(err, store.ErrNotFound) {
+			return planNoExiste, store.PlanMeta{}, nil
+		}
+
+		slog.Error(err.Error(), "objKey", objKey)
+		return planNoExiste, store.PlanMeta{}, fmt.Errorf("Error interno al comparar con planes ya existentes.")
 	}
 
-	for _, existObj := range bucket.Contents {
-		existObjHead, err := client.HeadObject(ctx, &s3.HeadObjectInput{
-			Bucket: aws.String(BUCKET),
-			Key:    existObj.Key,
-		})
+	if existente.ContentSha256 == "" {
+		slog.Warn("Plan existente sin metadato 'content-sha256'.", "objKey", objKey)
+		return planExisteContenidoDistinto, existente, nil
+	}
 
-		if err != nil {
-			slog.Error(err.Error(), "objKey", existObj.Key)
-			return false, fmt.Errorf("Error interno al comparar con planes ya existentes.")
-		}
+	if existente.ContentSha256 == contentSha256 {
+		return planExisteMismoContenido, existente, nil
+	}
 
-		meta := existObjHead.Metadata
+	return planExisteContenidoDistinto, existente, nil
+}
 
-		carrera, okCarrera := meta["carrera"]
-		numero, okNum := meta["cuatri-numero"]
-		anio, okAnio := meta["cuatri-anio"]
+// sha256HashB64 calcula el hash SHA-256 del contenido scrapeado,
+// codificado en base64, usado como metadato 'content-sha256' para
+// deduplicación idempotente.
+func sha256HashB64(contenido []byte) string {
+	suma := sha256.Sum256(contenido)
+	return base64.StdEncoding.EncodeToString(suma[:])
+}
 
-		if okCarrera && okNum && okAnio {
-			if newObj.Metadata["carrera"] == carrera &&
-				newObj.Metadata["cuatri-numero"] == numero &&
-				newObj.Metadata["cuatri-anio"] == anio {
+// planObjKeyYMeta arma el object key y la metadata de usuario de un plan a
+// partir de su carrera y cuatrimestre, compartido entre la subida directa
+// (handlerPost) y la finalización de una subida por partes
+// (handlerUploadsFinalizar).
+func planObjKeyYMeta(carreraOriginal string, cuatriNumero, cuatriAnio int, objBody []byte) (string, map[string]string) {
+	// Se eliminan los diacríticos para generar el file path (object key).
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	carrera, _, _ := transform.String(t, carreraOriginal)
+	carrera = strings.ToLower(strings.ReplaceAll(carrera, " ", "-"))
 
-				slog.Info(
-					"Cache hit de plan.",
-					"objKey", existObj.Key,
-				)
+	objKey := fmt.Sprintf("%v-%vC-%v.json", carrera, cuatriNumero, cuatriAnio)
 
-				return true, nil
-			}
-		}
+	// Se le hace encoding al valor del key de metadata 'carrera'. Esto debido a
+	// que AWS require este encoding cuando los valores de los headers (los
+	// metadatos son headers) contiene caracters no US-ASCII, como es el caso de
+	// la mayoría de los nombres de las carreras de la facultad.
+	//
+	// Realmente la SDK de S3 se encarga automáticamente de esto, pero por alguna
+	// razón me está arrojando un encoding erróneo de las tildes, así que
+	// prefiero hacerlo manual.
+	//
+	// Más información: https://docs.aws.amazon.com/AmazonS3/latest/userguide/UsingMetadata.html#UserMetadata
+	carreraB64 := base64.StdEncoding.EncodeToString([]byte(carreraOriginal))
+
+	planId := fmt.Sprintf("%v-%vC-%v", carrera, cuatriNumero, cuatriAnio)
+	contentSha256 := sha256HashB64(objBody)
+
+	objMeta := map[string]string{
+		"carrera":        carreraB64,
+		"cuatri-numero":  strconv.Itoa(cuatriNumero),
+		"cuatri-anio":    strconv.Itoa(cuatriAnio),
+		"plan-id":        planId,
+		"content-sha256": contentSha256,
 	}
 
-	return false, nil
+	return objKey, objMeta
 }
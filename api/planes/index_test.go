@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/regexPattern/fiuba-reviews-backend/api/accesskey"
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
+)
+
+func firmarRequest(t *testing.T, secretKey, accessKeyId string, datos []byte, timestamp time.Time) *http.Request {
+	t.Helper()
+
+	timestampStr := strconv.FormatInt(timestamp.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secretKey))
+	mac.Write(datos)
+	mac.Write([]byte(timestampStr))
+	firma := hex.EncodeToString(mac.Sum(nil))
+
+	r, err := http.NewRequest("POST", "/scraper-siu", bytes.NewReader(datos))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	r.Header.Set("Authorization", "FIUBA-HMAC-SHA256 key="+accessKeyId+",signature="+firma)
+	r.Header.Set("X-Fiuba-Timestamp", timestampStr)
+
+	return r
+}
+
+func TestAutenticarHmacFirmaValida(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	rec, err := accesskey.Issue(ctx, st, []string{"Ingeniería en Informática"}, "2026-07-29T00:00:00Z")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	datos := []byte(`{"carrera":"Ingeniería en Informática"}`)
+	r := firmarRequest(t, rec.SecretKey, rec.AccessKey, datos, time.Now())
+
+	resuelta, err := autenticarHmac(ctx, st, r, datos)
+	if err != nil {
+		t.Fatalf("autenticarHmac: %v", err)
+	}
+	if resuelta.AccessKey != rec.AccessKey {
+		t.Errorf("AccessKey resuelta = %q, querida %q", resuelta.AccessKey, rec.AccessKey)
+	}
+}
+
+func TestAutenticarHmacFirmaInvalida(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	rec, err := accesskey.Issue(ctx, st, []string{"Ingeniería en Informática"}, "2026-07-29T00:00:00Z")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	datos := []byte(`{"carrera":"Ingeniería en Informática"}`)
+	r := firmarRequest(t, rec.SecretKey, rec.AccessKey, datos, time.Now())
+
+	if _, err := autenticarHmac(ctx, st, r, []byte("datos distintos a los firmados")); err == nil {
+		t.Error("autenticarHmac con datos alterados devolvió nil, quería un error")
+	}
+}
+
+func TestAutenticarHmacTimestampDesfasado(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	rec, err := accesskey.Issue(ctx, st, []string{"Ingeniería en Informática"}, "2026-07-29T00:00:00Z")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+
+	datos := []byte(`{"carrera":"Ingeniería en Informática"}`)
+	r := firmarRequest(t, rec.SecretKey, rec.AccessKey, datos, time.Now().Add(-10*time.Minute))
+
+	if _, err := autenticarHmac(ctx, st, r, datos); err == nil {
+		t.Error("autenticarHmac con timestamp desfasado devolvió nil, quería un error")
+	}
+}
+
+func TestAutenticarHmacAccessKeyDesconocida(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	datos := []byte(`{"carrera":"Ingeniería en Informática"}`)
+	r := firmarRequest(t, "secreto-inventado", "clave-inexistente", datos, time.Now())
+
+	if _, err := autenticarHmac(ctx, st, r, datos); err == nil {
+		t.Error("autenticarHmac con access key desconocida devolvió nil, quería un error")
+	}
+}
+
+func TestPlanYaExiste(t *testing.T) {
+	st, err := store.NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	objKey := "informatica-1C-2026.json"
+
+	estado, _, err := planYaExiste(ctx, st, objKey, "hash-nuevo")
+	if err != nil {
+		t.Fatalf("planYaExiste sobre key inexistente: %v", err)
+	}
+	if estado != planNoExiste {
+		t.Errorf("estado = %v, quería planNoExiste", estado)
+	}
+
+	if err := st.Put(ctx, objKey, bytes.NewReader([]byte("{}")), map[string]string{"content-sha256": "hash-original"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	estado, existente, err := planYaExiste(ctx, st, objKey, "hash-original")
+	if err != nil {
+		t.Fatalf("planYaExiste con mismo contenido: %v", err)
+	}
+	if estado != planExisteMismoContenido {
+		t.Errorf("estado = %v, quería planExisteMismoContenido", estado)
+	}
+	if existente.ETag == "" {
+		t.Error("existente.ETag = \"\", quería el ETag del plan ya guardado")
+	}
+
+	estado, _, err = planYaExiste(ctx, st, objKey, "hash-distinto")
+	if err != nil {
+		t.Fatalf("planYaExiste con contenido distinto: %v", err)
+	}
+	if estado != planExisteContenidoDistinto {
+		t.Errorf("estado = %v, quería planExisteContenidoDistinto", estado)
+	}
+}
@@ -0,0 +1,343 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/regexPattern/fiuba-reviews-backend/api/accesskey"
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
+)
+
+// listBucketResult espeja el subset de la respuesta XML de S3 ListObjectsV2
+// que necesitan los clientes S3 existentes (aws-cli, boto3, rclone) para
+// navegar los planes scrapeados sin pasar por la API JSON.
+type listBucketResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Xmlns                 string         `xml:"xmlns,attr"`
+	Name                  string         `xml:"Name"`
+	Prefix                string         `xml:"Prefix"`
+	Delimiter             string         `xml:"Delimiter,omitempty"`
+	KeyCount              int            `xml:"KeyCount"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	ContinuationToken     string         `xml:"ContinuationToken,omitempty"`
+	NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	Contents              []listObject   `xml:"Contents"`
+	CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+}
+
+type listObject struct {
+	Key          string `xml:"Key"`
+	LastModified string `xml:"LastModified"`
+	ETag         string `xml:"ETag"`
+	Size         int64  `xml:"Size"`
+	StorageClass string `xml:"StorageClass"`
+}
+
+type commonPrefix struct {
+	Prefix string `xml:"Prefix"`
+}
+
+// HandlerS3List atiende GET /?list-type=2&prefix=&delimiter=/&continuation-token=,
+// hablando un subconjunto del protocolo REST de S3 sobre el bucket de
+// planes. Esto permite navegar los planes scrapeados con cualquier cliente
+// S3 existente (aws-cli, boto3, rclone) sin pasar por la API JSON.
+//
+// La paginación (continuation-token/max-keys) y los delimiters son
+// semántica nativa de ListObjectsV2 sin equivalente en Store, así que este
+// handler habla directo contra *s3.Client en vez de Store para el listado
+// en sí; sólo la resolución de la access key que firma el request (en
+// verifySigV4) pasa por Store. Correrlo con FIUBA_STORE=fs no alcanza: el
+// listado sigue requiriendo credenciales reales de AWS.
+func HandlerS3List(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+
+	if r.Method != "GET" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	if r.URL.Query().Get("list-type") != "2" {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte("Sólo se soporta ListObjectsV2 (list-type=2)."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	client := s3.NewFromConfig(cfg)
+
+	st, err := store.New(ctx, BUCKET)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	if err := verifySigV4(ctx, st, r); err != nil {
+		slog.Error(err.Error())
+
+		w.Header().Set("WWW-Authenticate", "AWS4-HMAC-SHA256")
+		w.WriteHeader(http.StatusForbidden)
+
+		_, werr := w.Write([]byte("Firma SigV4 inválida o ausente."))
+		if werr != nil {
+			slog.Error(werr.Error())
+		}
+
+		return
+	}
+
+	prefix := r.URL.Query().Get("prefix")
+	delimiter := r.URL.Query().Get("delimiter")
+	continuationToken := r.URL.Query().Get("continuation-token")
+
+	maxKeys := 1000
+	if v := r.URL.Query().Get("max-keys"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			maxKeys = n
+		}
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:  aws.String(BUCKET),
+		Prefix:  aws.String(prefix),
+		MaxKeys: aws.Int32(int32(maxKeys)),
+	}
+
+	if delimiter != "" {
+		input.Delimiter = aws.String(delimiter)
+	}
+	if continuationToken != "" {
+		input.ContinuationToken = aws.String(continuationToken)
+	}
+
+	out, err := client.ListObjectsV2(ctx, input)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	result := listBucketResult{
+		Xmlns:                 "http://s3.amazonaws.com/doc/2006-03-01/",
+		Name:                  BUCKET,
+		Prefix:                prefix,
+		Delimiter:             delimiter,
+		MaxKeys:               maxKeys,
+		IsTruncated:           aws.ToBool(out.IsTruncated),
+		ContinuationToken:     continuationToken,
+		NextContinuationToken: aws.ToString(out.NextContinuationToken),
+	}
+
+	// Igual que S3Store.List, sólo los planes ('{carrera}-{N}C-{anio}.json'
+	// sueltos en la raíz del bucket) se listan acá: el resto del bucket
+	// (access keys bajo 'accesskeys/', estado de subidas por partes bajo
+	// 'uploads/') no debe quedar expuesto a través de este endpoint S3-like,
+	// ni siquiera a un holder de access key con ACL acotada a una carrera.
+	for _, obj := range out.Contents {
+		key := aws.ToString(obj.Key)
+		if !store.EsPlanKey(key) {
+			continue
+		}
+
+		result.Contents = append(result.Contents, listObject{
+			Key:          key,
+			LastModified: aws.ToTime(obj.LastModified).Format("2006-01-02T15:04:05.000Z"),
+			ETag:         aws.ToString(obj.ETag),
+			Size:         aws.ToInt64(obj.Size),
+			StorageClass: string(obj.StorageClass),
+		})
+	}
+
+	for _, cp := range out.CommonPrefixes {
+		cpPrefix := aws.ToString(cp.Prefix)
+		if !store.EsPlanKey(cpPrefix) {
+			continue
+		}
+
+		result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: cpPrefix})
+	}
+
+	result.KeyCount = len(result.Contents)
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+
+	enc := xml.NewEncoder(w)
+	if err := enc.Encode(result); err != nil {
+		slog.Error(err.Error())
+	}
+}
+
+// verifySigV4 valida la cabecera 'Authorization: AWS4-HMAC-SHA256 ...' de un
+// request siguiendo el algoritmo de firma de AWS SigV4, para que el listado
+// sólo quede expuesto a quien tenga un par access-key/secret-key válido.
+func verifySigV4(ctx context.Context, st store.Store, r *http.Request) error {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 ") {
+		return fmt.Errorf("falta cabecera Authorization AWS4-HMAC-SHA256")
+	}
+
+	campos := map[string]string{}
+	for _, parte := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ",") {
+		kv := strings.SplitN(strings.TrimSpace(parte), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		campos[kv[0]] = kv[1]
+	}
+
+	credential, signedHeaders, signature := campos["Credential"], campos["SignedHeaders"], campos["Signature"]
+	if credential == "" || signedHeaders == "" || signature == "" {
+		return fmt.Errorf("cabecera Authorization incompleta")
+	}
+
+	credParts := strings.Split(credential, "/")
+	if len(credParts) != 5 {
+		return fmt.Errorf("credential scope malformado")
+	}
+
+	accessKeyId, fecha, region, servicio := credParts[0], credParts[1], credParts[2], credParts[3]
+
+	rec, err := accesskey.Lookup(ctx, st, accessKeyId)
+	if err != nil {
+		return fmt.Errorf("access key desconocida: %v", accessKeyId)
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	if amzDate == "" {
+		return fmt.Errorf("falta cabecera X-Amz-Date")
+	}
+
+	timestamp, err := time.Parse("20060102T150405Z", amzDate)
+	if err != nil {
+		return fmt.Errorf("X-Amz-Date inválida: %w", err)
+	}
+
+	skew := time.Since(timestamp)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > 5*time.Minute {
+		return fmt.Errorf("timestamp desfasado por más de 5 minutos")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		canonicalURI(r.URL.Path),
+		canonicalQueryString(r.URL.Query()),
+		canonicalHeaders(r, signedHeaders),
+		signedHeaders,
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	credentialScope := strings.Join([]string{fecha, region, servicio, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hex.EncodeToString(sha256Sum([]byte(canonicalRequest))),
+	}, "\n")
+
+	signingKey := derivarSigningKey(rec.SecretKey, fecha, region, servicio)
+	esperada := hex.EncodeToString(hmacSha256(signingKey, stringToSign))
+
+	if !hmac.Equal([]byte(esperada), []byte(signature)) {
+		return fmt.Errorf("firma inválida")
+	}
+
+	return nil
+}
+
+func canonicalURI(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+func canonicalQueryString(query url.Values) string {
+	claves := make([]string, 0, len(query))
+	for k := range query {
+		claves = append(claves, k)
+	}
+	sort.Strings(claves)
+
+	partes := make([]string, 0, len(claves))
+	for _, k := range claves {
+		valores := append([]string(nil), query[k]...)
+		sort.Strings(valores)
+		for _, v := range valores {
+			partes = append(partes, fmt.Sprintf("%s=%s", url.QueryEscape(k), url.QueryEscape(v)))
+		}
+	}
+
+	return strings.Join(partes, "&")
+}
+
+func canonicalHeaders(r *http.Request, signedHeaders string) string {
+	var b strings.Builder
+
+	for _, nombre := range strings.Split(signedHeaders, ";") {
+		var valor string
+		if strings.EqualFold(nombre, "host") {
+			valor = r.Host
+		} else {
+			valor = r.Header.Get(nombre)
+		}
+
+		b.WriteString(strings.ToLower(nombre))
+		b.WriteString(":")
+		b.WriteString(strings.TrimSpace(valor))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+func derivarSigningKey(secretKey, fecha, region, servicio string) []byte {
+	kDate := hmacSha256([]byte("AWS4"+secretKey), fecha)
+	kRegion := hmacSha256(kDate, region)
+	kService := hmacSha256(kRegion, servicio)
+	return hmacSha256(kService, "aws4_request")
+}
+
+func hmacSha256(key []byte, dato string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(dato))
+	return mac.Sum(nil)
+}
+
+func sha256Sum(dato []byte) []byte {
+	suma := sha256.Sum256(dato)
+	return suma[:]
+}
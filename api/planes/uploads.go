@@ -0,0 +1,416 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/regexPattern/fiuba-reviews-backend/api/store"
+	"github.com/regexPattern/fiuba-reviews/scraper"
+)
+
+const uploadsPrefix = "uploads/"
+
+// uploadSession es el estado de una subida por partes en progreso,
+// persistido como un objeto aparte en el bucket para sobrevivir a que el
+// handler se reinicie entre un PATCH y el siguiente (las funciones son
+// stateless entre invocaciones).
+type uploadSession struct {
+	UploadId string              `json:"uploadId"`
+	Offset   int64               `json:"offset"`
+	Parts    []uploadSessionPart `json:"parts"`
+}
+
+type uploadSessionPart struct {
+	PartNumber int32  `json:"partNumber"`
+	ETag       string `json:"etag"`
+}
+
+// handlerUploads implementa el protocolo de subida por partes, modelado en
+// el de blobs de Docker Registry: POST abre la sesión, uno o más PATCH van
+// subiendo pedazos (cada uno devuelve el offset actual en 'Range'), y PUT
+// con el digest esperado cierra la sesión y dispara el scrapeo sobre el
+// contenido ya ensamblado. Así ningún request individual tiene que traer el
+// dump completo del SIU (cada PATCH lee a memoria sólo su propio pedazo), y
+// una conexión caída puede resumirse en vez de perder la subida completa. El
+// PUT final sí lee entero a memoria el contenido ensamblado, para verificar
+// el digest y scrapearlo.
+func handlerUploads(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	// El store cubre la autenticación (lookup de access keys), el estado de
+	// sesión y la lectura/borrado del contenido ya ensamblado, que son simples
+	// objetos por key; sólo las primitivas de multipart upload en sí
+	// (Create/Upload/Complete/Abort) son nativas de S3 y van directo contra el
+	// bucket real de AWS. Como CompleteMultipartUpload deja el objeto
+	// ensamblado en ese bucket y el PUT final lo lee a través del store, este
+	// endpoint sólo completa una subida con FIUBA_STORE=s3: con fs o minio el
+	// objeto ensamblado no está en el store y la finalización responde 500.
+	st, err := store.New(ctx, BUCKET)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	client := s3.NewFromConfig(cfg)
+
+	uploadId := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/scraper/uploads"), "/")
+
+	switch {
+	case r.Method == "POST" && uploadId == "":
+		handlerUploadsStart(ctx, client, st, w, r)
+	case r.Method == "PATCH" && uploadId != "":
+		handlerUploadsPatch(ctx, client, st, w, r, uploadId)
+	case r.Method == "PUT" && uploadId != "":
+		handlerUploadsFinalizar(ctx, client, st, w, r, uploadId)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func handlerUploadsStart(ctx context.Context, client *s3.Client, st store.Store, w http.ResponseWriter, r *http.Request) {
+	// Se exige la misma cabecera HMAC que en una subida directa (autenticarHmac)
+	// desde el primer request, no sólo al finalizar: de otra forma cualquiera
+	// podría abrir multipart uploads sin límite contra el bucket antes de que
+	// exista siquiera un plan que autorizar contra una ACL.
+	if _, err := autenticarHmac(ctx, st, r, []byte{}); err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusUnauthorized)
+
+		if _, werr := w.Write([]byte(err.Error())); werr != nil {
+			slog.Error(werr.Error())
+		}
+
+		return
+	}
+
+	id, err := randomUploadId()
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	out, err := client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(BUCKET),
+		Key:         aws.String(blobKey(id)),
+		ContentType: aws.String("text/html"),
+	})
+
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	session := uploadSession{UploadId: aws.ToString(out.UploadId)}
+	if err := guardarSesion(ctx, st, id, session); err != nil {
+		slog.Error(err.Error())
+
+		abortarUpload(ctx, client, id, session.UploadId)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Location", "/scraper/uploads/"+id)
+	w.Header().Set("Range", "0-0")
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func handlerUploadsPatch(ctx context.Context, client *s3.Client, st store.Store, w http.ResponseWriter, r *http.Request, uploadId string) {
+	session, err := leerSesion(ctx, st, uploadId)
+	if err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	defer r.Body.Close()
+
+	chunk, err := io.ReadAll(r.Body)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Igual que en el POST de apertura, cada pedazo subido exige la firma
+	// HMAC sobre su propio contenido: un PATCH no autenticado no debe poder
+	// escribir bytes arbitrarios bajo 'uploads/' aunque ya conozca el uuid.
+	if _, err := autenticarHmac(ctx, st, r, chunk); err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+
+		w.WriteHeader(http.StatusUnauthorized)
+
+		if _, werr := w.Write([]byte(err.Error())); werr != nil {
+			slog.Error(werr.Error())
+		}
+
+		return
+	}
+
+	partNumber := int32(len(session.Parts) + 1)
+
+	partOut, err := client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     aws.String(BUCKET),
+		Key:        aws.String(blobKey(uploadId)),
+		UploadId:   aws.String(session.UploadId),
+		PartNumber: aws.Int32(partNumber),
+		Body:       bytes.NewReader(chunk),
+	})
+
+	if err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	session.Parts = append(session.Parts, uploadSessionPart{
+		PartNumber: partNumber,
+		ETag:       aws.ToString(partOut.ETag),
+	})
+	session.Offset += int64(len(chunk))
+
+	if err := guardarSesion(ctx, st, uploadId, session); err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Range", fmt.Sprintf("0-%d", session.Offset-1))
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func handlerUploadsFinalizar(ctx context.Context, client *s3.Client, st store.Store, w http.ResponseWriter, r *http.Request, uploadId string) {
+	digestParam := r.URL.Query().Get("digest")
+	if !strings.HasPrefix(digestParam, "sha256:") {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte("Se espera un query param 'digest' con formato 'sha256:<hex>'."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	accessKeyRec, err := autenticarHmac(ctx, st, r, []byte(digestParam))
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusUnauthorized)
+
+		_, werr := w.Write([]byte(err.Error()))
+		if werr != nil {
+			slog.Error(werr.Error())
+		}
+
+		return
+	}
+
+	session, err := leerSesion(ctx, st, uploadId)
+	if err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	parts := make([]types.CompletedPart, len(session.Parts))
+	for i, p := range session.Parts {
+		parts[i] = types.CompletedPart{PartNumber: aws.Int32(p.PartNumber), ETag: aws.String(p.ETag)}
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.ToInt32(parts[i].PartNumber) < aws.ToInt32(parts[j].PartNumber)
+	})
+
+	_, err = client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(BUCKET),
+		Key:             aws.String(blobKey(uploadId)),
+		UploadId:        aws.String(session.UploadId),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+	})
+
+	if err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// A partir de acá el contenido ya quedó ensamblado como un único objeto
+	// bajo 'uploads/': se borra junto con el estado de sesión al salir de
+	// este handler por cualquier camino (digest inválido, scrapeo fallido,
+	// ACL rechazada, Put final fallido, o éxito), para no dejar el dump
+	// crudo duplicado en el store indefinidamente.
+	defer func() {
+		if err := st.Delete(ctx, blobKey(uploadId)); err != nil {
+			slog.Error(err.Error(), "uploadId", uploadId)
+		}
+		if err := st.Delete(ctx, sesionKey(uploadId)); err != nil {
+			slog.Error(err.Error(), "uploadId", uploadId)
+		}
+	}()
+
+	ensamblado, err := st.Get(ctx, blobKey(uploadId))
+	if err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	defer ensamblado.Close()
+
+	contenidoSiu, err := io.ReadAll(ensamblado)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	suma := sha256.Sum256(contenidoSiu)
+	if hex.EncodeToString(suma[:]) != strings.TrimPrefix(digestParam, "sha256:") {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte("El digest del contenido ensamblado no coincide con el anunciado."))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	meta, err := scraper.ObtenerMetaData(string(contenidoSiu))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		_, err := w.Write([]byte(err.Error()))
+		if err != nil {
+			slog.Error(err.Error())
+		}
+
+		return
+	}
+
+	if !accessKeyRec.PuedeSubir(meta.Carrera) {
+		slog.Warn("Access key sin ACL para la carrera.", "carrera", meta.Carrera, "accessKey", accessKeyRec.AccessKey)
+
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	materias := scraper.ObtenerMaterias(meta.Cuatri.Contenido)
+	objBody, err := json.Marshal(materias)
+	if err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	objKey, objMeta := planObjKeyYMeta(meta.Carrera, meta.Cuatri.Numero, meta.Cuatri.Anio, objBody)
+
+	if err := st.Put(ctx, objKey, bytes.NewReader(objBody), objMeta); err != nil {
+		slog.Error(err.Error())
+
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	slog.Info(fmt.Sprintf("Escrito archivo '%v' vía subida por partes con éxito.", objKey))
+}
+
+// abortarUpload cancela un multipart upload ya creado en S3, para no dejar
+// partes huérfanas facturándose cuando el resto de la apertura de la sesión
+// falla.
+func abortarUpload(ctx context.Context, client *s3.Client, uploadId, s3UploadId string) {
+	_, err := client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(BUCKET),
+		Key:      aws.String(blobKey(uploadId)),
+		UploadId: aws.String(s3UploadId),
+	})
+
+	if err != nil {
+		slog.Error(err.Error(), "uploadId", uploadId)
+	}
+}
+
+func blobKey(uploadId string) string {
+	return uploadsPrefix + uploadId + ".blob"
+}
+
+func sesionKey(uploadId string) string {
+	return uploadsPrefix + uploadId + ".session.json"
+}
+
+func guardarSesion(ctx context.Context, st store.Store, uploadId string, session uploadSession) error {
+	body, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	return st.Put(ctx, sesionKey(uploadId), bytes.NewReader(body), nil)
+}
+
+func leerSesion(ctx context.Context, st store.Store, uploadId string) (uploadSession, error) {
+	obj, err := st.Get(ctx, sesionKey(uploadId))
+	if err != nil {
+		return uploadSession{}, fmt.Errorf("sesión de subida desconocida: %w", err)
+	}
+
+	defer obj.Close()
+
+	var session uploadSession
+	if err := json.NewDecoder(obj).Decode(&session); err != nil {
+		return uploadSession{}, err
+	}
+
+	return session, nil
+}
+
+func randomUploadId() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	// Variante y versión de UUIDv4, para que el id tenga el formato
+	// estándar esperado por clientes que lo muestren en logs.
+	buf[6] = (buf[6] & 0x0f) | 0x40
+	buf[8] = (buf[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16]), nil
+}
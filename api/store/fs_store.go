@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const metaSuffix = ".meta.json"
+
+// FSStore guarda cada plan como un archivo plano, con su metadata en un
+// sidecar '.meta.json' al lado. Pensado para desarrollo offline y CI, donde
+// no hay credenciales de AWS disponibles.
+type FSStore struct {
+	baseDir string
+}
+
+func NewFSStore(baseDir string) (*FSStore, error) {
+	if baseDir == "" {
+		baseDir = "./data/planes"
+	}
+
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creando directorio del store: %w", err)
+	}
+
+	return &FSStore{baseDir: baseDir}, nil
+}
+
+// List sólo recorre el nivel superior de baseDir, así que nunca ve las
+// access keys ni el estado de subidas por partes que también se guardan acá
+// (bajo 'accesskeys/' y 'uploads/' respectivamente): quedan en
+// subdirectorios propios, igual que en S3Store.List.
+func (st *FSStore) List(ctx context.Context) ([]PlanMeta, error) {
+	entradas, err := os.ReadDir(st.baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]PlanMeta, 0, len(entradas))
+
+	for _, entrada := range entradas {
+		if entrada.IsDir() || !strings.HasSuffix(entrada.Name(), metaSuffix) {
+			continue
+		}
+
+		key := strings.TrimSuffix(entrada.Name(), metaSuffix)
+
+		meta, err := st.Head(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+
+		metas = append(metas, meta)
+	}
+
+	return metas, nil
+}
+
+func (st *FSStore) Put(ctx context.Context, key string, body io.Reader, meta map[string]string) error {
+	contenido, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	// Misma semántica de precondiciones que S3Store.Put, para que el 412 de
+	// If-Match/If-None-Match (agregado en chunk0-1) también sea ejercitable
+	// contra el backend 'fs' en tests herméticos.
+	existente, err := st.Head(ctx, key)
+	existe := true
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		existe = false
+	}
+
+	if ifMatch := meta[MetaIfMatch]; ifMatch != "" {
+		if !existe || existente.ETag != ifMatch {
+			return ErrPreconditionFailed
+		}
+	}
+	if ifNoneMatch := meta[MetaIfNoneMatch]; ifNoneMatch != "" && existe {
+		return ErrPreconditionFailed
+	}
+
+	destino := filepath.Join(st.baseDir, key)
+	if err := os.MkdirAll(filepath.Dir(destino), 0o755); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(destino, contenido, 0o644); err != nil {
+		return err
+	}
+
+	userMeta := make(map[string]string, len(meta)+1)
+	for k, v := range meta {
+		if k == MetaIfMatch || k == MetaIfNoneMatch {
+			continue
+		}
+		userMeta[k] = v
+	}
+	userMeta["etag"] = fmt.Sprintf("%x", md5.Sum(contenido))
+
+	metaJson, err := json.Marshal(userMeta)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(destino+metaSuffix, metaJson, 0o644)
+}
+
+func (st *FSStore) Head(ctx context.Context, key string) (PlanMeta, error) {
+	raw, err := os.ReadFile(filepath.Join(st.baseDir, key+metaSuffix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return PlanMeta{}, ErrNotFound
+		}
+		return PlanMeta{}, err
+	}
+
+	var userMeta map[string]string
+	if err := json.Unmarshal(raw, &userMeta); err != nil {
+		return PlanMeta{}, err
+	}
+
+	etag := userMeta["etag"]
+	delete(userMeta, "etag")
+
+	return planMetaDesdeUserMeta(key, userMeta, etag), nil
+}
+
+func (st *FSStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(st.baseDir, key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (st *FSStore) Delete(ctx context.Context, key string) error {
+	destino := filepath.Join(st.baseDir, key)
+
+	if err := os.Remove(destino); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Remove(destino + metaSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestFSStorePutGetHeadRoundtrip(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	contenido := []byte(`{"carrera":"Ingeniería en Informática"}`)
+
+	if err := st.Put(ctx, "informatica-1C-2026.json", bytes.NewReader(contenido), map[string]string{"plan-id": "abc"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	obj, err := st.Get(ctx, "informatica-1C-2026.json")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer obj.Close()
+
+	leido, err := io.ReadAll(obj)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if !bytes.Equal(leido, contenido) {
+		t.Errorf("contenido leído = %q, querido %q", leido, contenido)
+	}
+
+	meta, err := st.Head(ctx, "informatica-1C-2026.json")
+	if err != nil {
+		t.Fatalf("Head: %v", err)
+	}
+	if meta.PlanId != "abc" {
+		t.Errorf("meta.PlanId = %q, querido %q", meta.PlanId, "abc")
+	}
+}
+
+func TestFSStoreGetHeadNotFound(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if _, err := st.Get(ctx, "no-existe.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get de key inexistente = %v, quería ErrNotFound", err)
+	}
+	if _, err := st.Head(ctx, "no-existe.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Head de key inexistente = %v, quería ErrNotFound", err)
+	}
+}
+
+func TestFSStorePutIfNoneMatchRechazaSobreescritura(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	key := "informatica-1C-2026.json"
+
+	if err := st.Put(ctx, key, bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("Put inicial: %v", err)
+	}
+
+	err = st.Put(ctx, key, bytes.NewReader([]byte("v2")), map[string]string{MetaIfNoneMatch: "*"})
+	if !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Put con If-None-Match sobre key existente = %v, quería ErrPreconditionFailed", err)
+	}
+}
+
+func TestFSStorePutIfMatchRequiereEtagVigente(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	key := "informatica-1C-2026.json"
+
+	if err := st.Put(ctx, key, bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("Put inicial: %v", err)
+	}
+
+	if err := st.Put(ctx, key, bytes.NewReader([]byte("v2")), map[string]string{MetaIfMatch: "etag-viejo-inventado"}); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("Put con If-Match desactualizado = %v, quería ErrPreconditionFailed", err)
+	}
+
+	meta, err := st.Head(ctx, key)
+	if err != nil {
+		t.Fatalf("Head: %v", err)
+	}
+
+	if err := st.Put(ctx, key, bytes.NewReader([]byte("v3")), map[string]string{MetaIfMatch: meta.ETag}); err != nil {
+		t.Errorf("Put con If-Match vigente = %v, quería nil", err)
+	}
+}
+
+func TestFSStoreListIgnoraAccessKeysYUploads(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+
+	if err := st.Put(ctx, "informatica-1C-2026.json", bytes.NewReader([]byte("{}")), nil); err != nil {
+		t.Fatalf("Put plan: %v", err)
+	}
+	if err := st.Put(ctx, "accesskeys/clave.json", bytes.NewReader([]byte("{}")), nil); err != nil {
+		t.Fatalf("Put access key: %v", err)
+	}
+	if err := st.Put(ctx, "uploads/abc.session.json", bytes.NewReader([]byte("{}")), nil); err != nil {
+		t.Fatalf("Put sesión de subida: %v", err)
+	}
+
+	metas, err := st.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	if len(metas) != 1 || metas[0].Key != "informatica-1C-2026.json" {
+		t.Errorf("List devolvió %+v, quería sólo el plan", metas)
+	}
+}
+
+func TestFSStoreDeleteBorraObjetoYMeta(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	ctx := context.Background()
+	key := "uploads/abc.blob"
+
+	if err := st.Put(ctx, key, bytes.NewReader([]byte("contenido")), nil); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	if err := st.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	if _, err := st.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get tras Delete = %v, quería ErrNotFound", err)
+	}
+	if _, err := st.Head(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Head tras Delete = %v, quería ErrNotFound", err)
+	}
+}
+
+func TestFSStoreDeleteDeKeyInexistenteEsIdempotente(t *testing.T) {
+	st, err := NewFSStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFSStore: %v", err)
+	}
+
+	if err := st.Delete(context.Background(), "uploads/no-existe.blob"); err != nil {
+		t.Errorf("Delete de key inexistente = %v, quería nil", err)
+	}
+}
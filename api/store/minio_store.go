@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// MinioStore habla el mismo protocolo S3 que S3Store, pero apuntado a un
+// endpoint propio (minio o fake-gcs-server en modo S3) vía path-style
+// addressing. Pensado para tests de integración herméticos, sin credenciales
+// reales de AWS.
+type MinioStore struct {
+	*S3Store
+}
+
+func NewMinioStore(ctx context.Context, bucket string) (*MinioStore, error) {
+	endpoint := os.Getenv("FIUBA_MINIO_ENDPOINT")
+	if endpoint == "" {
+		endpoint = "http://localhost:9000"
+	}
+
+	accessKey := os.Getenv("FIUBA_MINIO_ACCESS_KEY")
+	secretKey := os.Getenv("FIUBA_MINIO_SECRET_KEY")
+
+	cfg, err := config.LoadDefaultConfig(
+		ctx,
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		o.BaseEndpoint = aws.String(endpoint)
+		o.UsePathStyle = true
+	})
+
+	return &MinioStore{S3Store: &S3Store{client: client, bucket: bucket}}, nil
+}
@@ -0,0 +1,168 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// S3Store es el backend original, contra el bucket real de planes.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+}
+
+func NewS3Store(ctx context.Context, bucket string) (*S3Store, error) {
+	cfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &S3Store{client: s3.NewFromConfig(cfg), bucket: bucket}, nil
+}
+
+func (st *S3Store) List(ctx context.Context) ([]PlanMeta, error) {
+	bucket, err := st.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(st.bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]PlanMeta, 0, len(bucket.Contents))
+
+	for _, obj := range bucket.Contents {
+		key := aws.ToString(obj.Key)
+
+		// Los planes viven sueltos en la raíz del bucket ('{carrera}-{N}C-{anio}.json');
+		// todo lo demás que comparte bucket (access keys bajo 'accesskeys/',
+		// estado de subidas por partes bajo 'uploads/') vive bajo un prefix
+		// propio y no debe aparecer en el listado público de planes.
+		if !EsPlanKey(key) {
+			continue
+		}
+
+		meta, err := st.Head(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		metas = append(metas, meta)
+	}
+
+	return metas, nil
+}
+
+// EsPlanKey distingue un object key de plan de cualquier otra cosa que
+// comparta el mismo bucket/store (access keys, estado de subidas por
+// partes), que siempre vive bajo un prefix con '/'. Exportada para que otros
+// puntos de entrada que listan el bucket directamente (como HandlerS3List)
+// apliquen el mismo filtro.
+func EsPlanKey(key string) bool {
+	return !strings.Contains(key, "/")
+}
+
+func (st *S3Store) Put(ctx context.Context, key string, body io.Reader, meta map[string]string) error {
+	userMeta := make(map[string]string, len(meta))
+	for k, v := range meta {
+		if k == MetaIfMatch || k == MetaIfNoneMatch {
+			continue
+		}
+		userMeta[k] = v
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:          aws.String(st.bucket),
+		Key:             aws.String(key),
+		ContentType:     aws.String("application/json"),
+		ContentLanguage: aws.String("es"),
+		Metadata:        userMeta,
+		Body:            body,
+	}
+
+	if v, ok := meta[MetaIfMatch]; ok && v != "" {
+		input.IfMatch = aws.String(v)
+	}
+	if v, ok := meta[MetaIfNoneMatch]; ok && v != "" {
+		input.IfNoneMatch = aws.String(v)
+	}
+
+	_, err := st.client.PutObject(ctx, input)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "PreconditionFailed" {
+			return ErrPreconditionFailed
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (st *S3Store) Head(ctx context.Context, key string) (PlanMeta, error) {
+	out, err := st.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(st.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound" {
+			return PlanMeta{}, ErrNotFound
+		}
+		return PlanMeta{}, err
+	}
+
+	return planMetaDesdeUserMeta(key, out.Metadata, aws.ToString(out.ETag)), nil
+}
+
+func (st *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := st.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(st.bucket),
+		Key:    aws.String(key),
+	})
+
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	return out.Body, nil
+}
+
+func (st *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := st.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(st.bucket),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
+
+// planMetaDesdeUserMeta arma un PlanMeta a partir de la metadata de usuario
+// de S3 (o de cualquier backend compatible, como minio).
+func planMetaDesdeUserMeta(key string, userMeta map[string]string, etag string) PlanMeta {
+	carrera, _ := base64.StdEncoding.DecodeString(userMeta["carrera"])
+	numero, _ := strconv.Atoi(userMeta["cuatri-numero"])
+	anio, _ := strconv.Atoi(userMeta["cuatri-anio"])
+
+	return PlanMeta{
+		Key:           key,
+		Carrera:       string(carrera),
+		CuatriNumero:  numero,
+		CuatriAnio:    anio,
+		PlanId:        userMeta["plan-id"],
+		ContentSha256: userMeta["content-sha256"],
+		ETag:          etag,
+	}
+}
@@ -0,0 +1,64 @@
+// Package store abstrae el backend donde se persisten los planes
+// scrapeados detrás de una interfaz chica, para poder correr la API
+// localmente (sin credenciales de AWS) y para que los tests de los
+// handlers sean herméticos.
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// PlanMeta es la metadata de un plan almacenado, independiente del backend
+// que la sirva.
+type PlanMeta struct {
+	Key           string
+	Carrera       string
+	CuatriNumero  int
+	CuatriAnio    int
+	PlanId        string
+	ContentSha256 string
+	ETag          string
+}
+
+// Metadata reservada que Put interpreta como precondiciones en vez de
+// guardarla como metadata de usuario. Todos los backends (S3Store, FSStore,
+// y MinioStore por heredar de S3Store) la entienden.
+const (
+	MetaIfMatch     = "if-match"
+	MetaIfNoneMatch = "if-none-match"
+)
+
+var (
+	ErrNotFound           = errors.New("store: objeto no encontrado")
+	ErrPreconditionFailed = errors.New("store: precondición falló")
+)
+
+// Store es el punto de extensión para los distintos backends de
+// almacenamiento de planes.
+type Store interface {
+	List(ctx context.Context) ([]PlanMeta, error)
+	Put(ctx context.Context, key string, body io.Reader, meta map[string]string) error
+	Head(ctx context.Context, key string) (PlanMeta, error)
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// New resuelve el backend a usar según la variable de entorno
+// FIUBA_STORE (s3|fs|minio), por defecto s3.
+func New(ctx context.Context, bucket string) (Store, error) {
+	switch strings.ToLower(os.Getenv("FIUBA_STORE")) {
+	case "", "s3":
+		return NewS3Store(ctx, bucket)
+	case "fs":
+		return NewFSStore(os.Getenv("FIUBA_STORE_DIR"))
+	case "minio":
+		return NewMinioStore(ctx, bucket)
+	default:
+		return nil, fmt.Errorf("FIUBA_STORE desconocido: %v", os.Getenv("FIUBA_STORE"))
+	}
+}